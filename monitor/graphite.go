@@ -0,0 +1,57 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// GraphiteReporter reports metrics to a Graphite carrier over TCP,
+// using "name value timestamp" framing, one line per metric.
+type GraphiteReporter struct {
+	addr string
+}
+
+// NewGraphiteReporter returns a reporter that dials addr (host:port)
+// fresh on every Report call, so a long-lived Graphite carrier
+// restart doesn't require recreating the reporter.
+func NewGraphiteReporter(addr string) (r *GraphiteReporter) {
+	return &GraphiteReporter{addr: addr}
+}
+
+// Report writes every metric as "head.leaf value unix-ts\n" over a
+// short-lived TCP connection.
+func (r *GraphiteReporter) Report(metrics map[string]TypedValue) (err error) {
+	conn, err := net.Dial("tcp", r.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ts := time.Now().Unix()
+
+	var buf bytes.Buffer
+	for k, tv := range metrics {
+		head, leaf := splitKey(k)
+		fmt.Fprintf(&buf, "%s.%s %d %d\n", graphiteEscape(head), graphiteEscape(leaf), tv.Value, ts)
+	}
+
+	_, err = conn.Write(buf.Bytes())
+	return err
+}
+
+// graphiteEscape sanitizes a metric name component for Graphite's
+// "name value timestamp" framing, where spaces and newlines are the
+// delimiters. Graphite has no escaping syntax, so they're replaced
+// with "_" instead.
+var graphiteReplacer = strings.NewReplacer(
+	" ", "_",
+	"\t", "_",
+	"\n", "_",
+)
+
+func graphiteEscape(s string) string {
+	return graphiteReplacer.Replace(s)
+}
@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// histogramSize is the number of samples kept in a histogram's
+// uniform reservoir.
+const histogramSize = 1028
+
+// HistogramSnapshot is a point-in-time summary of the samples held in
+// a Histogram's reservoir.
+type HistogramSnapshot struct {
+	Min    int64
+	Max    int64
+	Mean   float64
+	StdDev float64
+	P50    int64
+	P95    int64
+	P99    int64
+}
+
+//   histogram
+// -------------
+
+// histogram keeps a fixed-size uniform reservoir of samples, built
+// with Vitter's Algorithm R, and reports aggregates over it.
+type histogram struct {
+	mtx       sync.Mutex
+	reservoir [histogramSize]int64
+	count     int64
+}
+
+func newHistogram() *histogram {
+	return &histogram{}
+}
+
+// Track adds a sample to the reservoir, replacing a random existing
+// slot once the reservoir is full so that every sample seen so far
+// has an equal chance of being retained.
+func (h *histogram) Track(n int64) {
+	h.mtx.Lock()
+
+	i := h.count
+	h.count++
+
+	if i < histogramSize {
+		h.reservoir[i] = n
+	} else if j := rand.Intn(int(i) + 1); j < histogramSize {
+		h.reservoir[j] = n
+	}
+
+	h.mtx.Unlock()
+}
+
+// Value returns the mean of the samples in the reservoir so a
+// histogram can still satisfy the metric interface.
+func (h *histogram) Value() (val int64) {
+	return int64(h.Snapshot().Mean)
+}
+
+func (h *histogram) Kind() (t Type) {
+	return Histogram
+}
+
+// Snapshot computes min/max/mean/stddev/p50/p95/p99 over a sorted
+// copy of the current reservoir.
+func (h *histogram) Snapshot() (snap HistogramSnapshot) {
+	h.mtx.Lock()
+	n := h.count
+	if n > histogramSize {
+		n = histogramSize
+	}
+	vals := make([]int64, n)
+	copy(vals, h.reservoir[:n])
+	h.mtx.Unlock()
+
+	return snapshotOf(vals)
+}
+
+// snapshotOf computes a HistogramSnapshot from an unsorted slice of
+// samples, sorting it in place.
+func snapshotOf(vals []int64) (snap HistogramSnapshot) {
+	if len(vals) == 0 {
+		return snap
+	}
+
+	sort.Slice(vals, func(i, j int) bool { return vals[i] < vals[j] })
+
+	var sum float64
+	for _, v := range vals {
+		sum += float64(v)
+	}
+	mean := sum / float64(len(vals))
+
+	var variance float64
+	for _, v := range vals {
+		d := float64(v) - mean
+		variance += d * d
+	}
+	variance /= float64(len(vals))
+
+	snap.Min = vals[0]
+	snap.Max = vals[len(vals)-1]
+	snap.Mean = mean
+	snap.StdDev = math.Sqrt(variance)
+	snap.P50 = percentile(vals, 0.50)
+	snap.P95 = percentile(vals, 0.95)
+	snap.P99 = percentile(vals, 0.99)
+
+	return snap
+}
+
+// percentile returns the value at the given percentile (0..1) of a
+// sorted slice.
+func percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	i := int(p * float64(len(sorted)))
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+
+	return sorted[i]
+}
@@ -0,0 +1,158 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Handler returns an http.Handler that exposes the default metric
+// store in Prometheus text exposition format. It is a thin wrapper
+// around PrometheusHandler kept around so call sites that just want
+// "the" metrics handler don't need to know the export format name.
+func Handler() http.Handler {
+	return store.Handler()
+}
+
+// PrometheusHandler returns an http.Handler that exposes the default
+// metric store in Prometheus text exposition format.
+func PrometheusHandler() http.Handler {
+	return store.PrometheusHandler()
+}
+
+// Handler returns an http.Handler that exposes this store, and all of
+// its sub-stores, in Prometheus text exposition format.
+func (s *Store) Handler() http.Handler {
+	return s.PrometheusHandler()
+}
+
+// PrometheusHandler returns an http.Handler that exposes this store,
+// and all of its sub-stores, in Prometheus text exposition format.
+// Sub-store heads are rendered as a "store" label rather than being
+// folded into the metric name, so a single metric name can be
+// aggregated across sub-stores in Grafana/Prometheus.
+func (s *Store) PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		s.writePrometheus(w)
+	})
+}
+
+// promSample is one store's value for a metric name.
+type promSample struct {
+	store string
+	value int64
+}
+
+// promInfoSample is one store's label set for an info metric name.
+type promInfoSample struct {
+	store  string
+	labels map[string]string
+}
+
+// writePrometheus renders this store's metrics, and every sub-store's
+// metrics recursively, in Prometheus text exposition format. Samples
+// are collected across the whole recursion before anything is
+// written, so every sample for a metric name is grouped under that
+// name's single "# TYPE" line — strict OpenMetrics parsers reject a
+// family that reappears after another family's samples.
+func (s *Store) writePrometheus(w io.Writer) {
+	kinds := map[string]Type{}
+	samples := map[string][]promSample{}
+	infos := map[string][]promInfoSample{}
+	s.collectPrometheus(kinds, samples, infos)
+
+	for name, pts := range samples {
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, promType(kinds[name]))
+		for _, pt := range pts {
+			fmt.Fprintf(w, "%s{store=%q} %d\n", name, pt.store, pt.value)
+		}
+	}
+
+	for name, pts := range infos {
+		fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+		for _, pt := range pts {
+			fmt.Fprintf(w, "%s{store=%q%s} 1\n", name, pt.store, promLabels(pt.labels))
+		}
+	}
+}
+
+// collectPrometheus gathers this store's samples, and every
+// sub-store's samples recursively, keyed by their sanitized
+// Prometheus metric name.
+func (s *Store) collectPrometheus(kinds map[string]Type, samples map[string][]promSample, infos map[string][]promInfoSample) {
+	for k, tv := range s.TypedValues() {
+		head, leaf := splitKey(k)
+		name := promName(leaf)
+
+		kinds[name] = tv.Type
+		samples[name] = append(samples[name], promSample{store: head, value: tv.Value})
+	}
+
+	for k, labels := range s.InfoValues() {
+		head, leaf := splitKey(k)
+		name := promName(leaf)
+
+		infos[name] = append(infos[name], promInfoSample{store: head, labels: labels})
+	}
+
+	for _, sub := range s.childStores() {
+		sub.collectPrometheus(kinds, samples, infos)
+	}
+}
+
+// promLabels renders a label set as a ",k=\"v\",..." suffix to append
+// after the store label in a Prometheus metric line.
+func promLabels(labels map[string]string) string {
+	var b strings.Builder
+	for k, v := range labels {
+		fmt.Fprintf(&b, ",%s=%q", promName(k), v)
+	}
+
+	return b.String()
+}
+
+// splitKey splits a "head:k" registry key back into its head and leaf
+// metric name.
+func splitKey(k string) (head, leaf string) {
+	parts := strings.SplitN(k, ":", 2)
+	if len(parts) != 2 {
+		return "", k
+	}
+
+	return parts[0], parts[1]
+}
+
+// promType maps a monitor.Type to the Prometheus "# TYPE" keyword.
+func promType(t Type) string {
+	switch t {
+	case Counter:
+		return "counter"
+	default:
+		return "gauge"
+	}
+}
+
+// promName sanitizes a metric leaf name into a valid Prometheus
+// metric name: [a-zA-Z_][a-zA-Z0-9_]*.
+func promName(k string) string {
+	b := make([]byte, len(k))
+	for i := 0; i < len(k); i++ {
+		c := k[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+			b[i] = c
+		case c >= '0' && c <= '9' && i > 0:
+			b[i] = c
+		default:
+			b[i] = '_'
+		}
+	}
+
+	if len(b) == 0 {
+		return "_"
+	}
+
+	return string(b)
+}
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestPrometheusHandlerCounterIsCumulative scrapes the same store three
+// times in a row and checks the exported counter doesn't reset between
+// scrapes, i.e. the handler reads metrics non-destructively.
+func TestPrometheusHandlerCounterIsCumulative(t *testing.T) {
+	s := newStore("test")
+	s.Register("reqs", Counter)
+	s.Track("reqs", 100)
+
+	h := s.PrometheusHandler()
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+		body := rec.Body.String()
+		if !strings.Contains(body, `reqs{store="test"} 100`) {
+			t.Fatalf("scrape %d: expected cumulative counter of 100, got body:\n%s", i, body)
+		}
+	}
+}
+
+// TestPrometheusHandlerGroupsSamplesByName ensures every sample for a
+// metric name is contiguous, with a single "# TYPE" line, even when
+// the name is shared across sub-stores and another metric name's
+// samples fall in between them in the store tree.
+func TestPrometheusHandlerGroupsSamplesByName(t *testing.T) {
+	s := newStore("app")
+	worker := s.New("worker")
+
+	s.Register("reqs", Counter)
+	s.Track("reqs", 1)
+
+	s.Register("zmem", Gauge)
+	s.Track("zmem", 2)
+
+	worker.Register("reqs", Counter)
+	worker.Track("reqs", 3)
+
+	rec := httptest.NewRecorder()
+	s.PrometheusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	lines := strings.Split(strings.TrimRight(body, "\n"), "\n")
+
+	typeCount := 0
+	firstReqs, lastReqs := -1, -1
+	for i, line := range lines {
+		if line == "# TYPE reqs counter" {
+			typeCount++
+		}
+		if strings.HasPrefix(line, "reqs{") {
+			if firstReqs == -1 {
+				firstReqs = i
+			}
+			lastReqs = i
+		}
+	}
+
+	if typeCount != 1 {
+		t.Fatalf("expected exactly one \"# TYPE reqs counter\" line, got %d:\n%s", typeCount, body)
+	}
+
+	for i := firstReqs; i <= lastReqs; i++ {
+		if line := lines[i]; line != "# TYPE reqs counter" && !strings.HasPrefix(line, "reqs{") {
+			t.Fatalf("reqs family is not contiguous, found %q between its samples:\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// InfluxDBReporter reports metrics to an InfluxDB HTTP write endpoint
+// using the line protocol, with the originating sub-store's head
+// carried as a "store" tag rather than folded into the measurement
+// name.
+type InfluxDBReporter struct {
+	url    string
+	db     string
+	client *http.Client
+}
+
+// NewInfluxDBReporter returns a reporter that writes to the InfluxDB
+// instance at url (e.g. "http://localhost:8086") and database db.
+func NewInfluxDBReporter(url, db string) (r *InfluxDBReporter) {
+	return &InfluxDBReporter{
+		url:    url,
+		db:     db,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Report writes every metric as a line protocol point and POSTs the
+// batch to InfluxDB's /write endpoint.
+func (r *InfluxDBReporter) Report(metrics map[string]TypedValue) (err error) {
+	ts := time.Now().UnixNano()
+
+	var buf bytes.Buffer
+	for k, tv := range metrics {
+		head, leaf := splitKey(k)
+		fmt.Fprintf(&buf, "%s,store=%s value=%d %d\n", influxEscape(leaf), influxEscape(head), tv.Value, ts)
+	}
+
+	resp, err := r.client.Post(r.url+"/write?db="+r.db, "text/plain", &buf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("monitor: influxdb write failed with status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// ReportInfo writes every info metric as a line protocol point with
+// its labels as tags and a constant field value of 1, Prometheus
+// GaugeInfo-style.
+func (r *InfluxDBReporter) ReportInfo(infos map[string]map[string]string) (err error) {
+	ts := time.Now().UnixNano()
+
+	var buf bytes.Buffer
+	for k, labels := range infos {
+		head, leaf := splitKey(k)
+
+		fmt.Fprintf(&buf, "%s,store=%s", influxEscape(leaf), influxEscape(head))
+		for lk, lv := range labels {
+			fmt.Fprintf(&buf, ",%s=%s", influxEscape(lk), influxEscape(lv))
+		}
+		fmt.Fprintf(&buf, " value=1 %d\n", ts)
+	}
+
+	resp, err := r.client.Post(r.url+"/write?db="+r.db, "text/plain", &buf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("monitor: influxdb write failed with status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// influxEscape escapes the characters that are significant to the
+// line protocol grammar (commas, spaces and equals signs separate
+// measurements/tags/fields) in a measurement name, tag key, tag
+// value, or field key so a sub-store head or info label can't break
+// the tag set it's written into.
+var influxReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`,`, `\,`,
+	` `, `\ `,
+	`=`, `\=`,
+)
+
+func influxEscape(s string) string {
+	return influxReplacer.Replace(s)
+}
@@ -0,0 +1,44 @@
+package monitor
+
+import "sync"
+
+//   infoMetric
+// --------------
+
+// infoMetric holds a set of string labels (build SHA, version,
+// region, hostname, ...) rather than a number. It does not implement
+// the metric interface: it is registered and read through its own
+// RegisterInfo/UpdateInfo/InfoValues path on Store, since a label set
+// can't be folded into a single int64.
+type infoMetric struct {
+	mtx  sync.Mutex
+	vals map[string]string
+}
+
+func newInfoMetric(labels map[string]string) *infoMetric {
+	return &infoMetric{vals: copyLabels(labels)}
+}
+
+func (m *infoMetric) update(labels map[string]string) {
+	vals := copyLabels(labels)
+
+	m.mtx.Lock()
+	m.vals = vals
+	m.mtx.Unlock()
+}
+
+func (m *infoMetric) labels() (res map[string]string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return copyLabels(m.vals)
+}
+
+func copyLabels(labels map[string]string) (res map[string]string) {
+	res = make(map[string]string, len(labels))
+	for k, v := range labels {
+		res[k] = v
+	}
+
+	return res
+}
@@ -0,0 +1,19 @@
+package monitor
+
+import "testing"
+
+func TestStoreInfo(t *testing.T) {
+	s := newStore("test")
+	s.RegisterInfo("build", map[string]string{"sha": "abc123"})
+	s.UpdateInfo("build", map[string]string{"sha": "def456", "version": "1.2.3"})
+
+	labels := s.InfoValues()["test:build"]
+	if labels["sha"] != "def456" || labels["version"] != "1.2.3" {
+		t.Fatalf("unexpected labels: %#v", labels)
+	}
+
+	labels["sha"] = "mutated"
+	if got := s.InfoValues()["test:build"]["sha"]; got != "def456" {
+		t.Fatalf("InfoValues should return a copy, got %q", got)
+	}
+}
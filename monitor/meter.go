@@ -0,0 +1,140 @@
+package monitor
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// meterTickInterval is how often the meter arbiter recomputes the
+// EWMAs for every registered meter.
+const meterTickInterval = 5 * time.Second
+
+// EWMA decay constants for the 1/5/15-minute moving averages, derived
+// from alpha = 1 - exp(-tickInterval/60/N) for N in {1, 5, 15} minutes.
+var (
+	alpha1  = 1 - math.Exp(-5.0/60.0/1)
+	alpha5  = 1 - math.Exp(-5.0/60.0/5)
+	alpha15 = 1 - math.Exp(-5.0/60.0/15)
+)
+
+//   meter
+// --------
+
+// meter tracks the 1/5/15-minute exponentially-weighted moving
+// average rate of tracked events (the sum of n over Track calls),
+// alongside the lifetime total.
+type meter struct {
+	mtx       sync.Mutex
+	count     int64
+	uncounted int64
+	init      bool
+	rate1     float64
+	rate5     float64
+	rate15    float64
+}
+
+// MeterSnapshot is a point-in-time summary of a meter.
+type MeterSnapshot struct {
+	Count  int64
+	Rate1  float64
+	Rate5  float64
+	Rate15 float64
+}
+
+func newMeter() *meter {
+	m := &meter{}
+	registerTicker(m)
+	return m
+}
+
+// Track records n events. n is typically 1, but larger batches are
+// supported.
+func (m *meter) Track(n int64) {
+	atomic.AddInt64(&m.count, n)
+	atomic.AddInt64(&m.uncounted, n)
+}
+
+// Value returns the lifetime count of tracked events.
+func (m *meter) Value() (val int64) {
+	return atomic.LoadInt64(&m.count)
+}
+
+func (m *meter) Kind() (t Type) {
+	return Meter
+}
+
+// Snapshot returns the current EWMA rates, in events/second.
+func (m *meter) Snapshot() (snap MeterSnapshot) {
+	m.mtx.Lock()
+	snap = MeterSnapshot{
+		Count:  atomic.LoadInt64(&m.count),
+		Rate1:  m.rate1,
+		Rate5:  m.rate5,
+		Rate15: m.rate15,
+	}
+	m.mtx.Unlock()
+
+	return snap
+}
+
+// tick folds the events accumulated since the last tick into the
+// 1/5/15-minute EWMAs.
+func (m *meter) tick() {
+	count := atomic.SwapInt64(&m.uncounted, 0)
+	instantRate := float64(count) / meterTickInterval.Seconds()
+
+	m.mtx.Lock()
+	if m.init {
+		m.rate1 += alpha1 * (instantRate - m.rate1)
+		m.rate5 += alpha5 * (instantRate - m.rate5)
+		m.rate15 += alpha15 * (instantRate - m.rate15)
+	} else {
+		m.rate1, m.rate5, m.rate15 = instantRate, instantRate, instantRate
+		m.init = true
+	}
+	m.mtx.Unlock()
+}
+
+//   arbiter
+// -----------
+
+// ticker is implemented by metrics that need periodic upkeep driven
+// by the meter arbiter, such as folding accumulated counts into an
+// EWMA.
+type ticker interface {
+	tick()
+}
+
+// arbiter ticks every registered meter/timer on a single shared
+// goroutine, rather than running one goroutine per metric.
+var arbiter = &meterArbiter{}
+
+type meterArbiter struct {
+	mtx     sync.Mutex
+	once    sync.Once
+	tickers []ticker
+}
+
+func registerTicker(t ticker) {
+	arbiter.mtx.Lock()
+	arbiter.tickers = append(arbiter.tickers, t)
+	arbiter.mtx.Unlock()
+
+	arbiter.once.Do(arbiter.start)
+}
+
+func (a *meterArbiter) start() {
+	go func() {
+		for range time.Tick(meterTickInterval) {
+			a.mtx.Lock()
+			tickers := a.tickers
+			a.mtx.Unlock()
+
+			for _, t := range tickers {
+				t.tick()
+			}
+		}
+	}()
+}
@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -16,6 +17,10 @@ const (
 	Gauge Type = iota
 	Counter
 	Rate
+	Histogram
+	Timer
+	Meter
+	Info
 )
 
 var (
@@ -38,47 +43,95 @@ func Track(k string, n int64) {
 	store.Track(k, n)
 }
 
+// RegisterInfo registers a labelled info metric, such as a build SHA
+// or version, using the default metric store
+func RegisterInfo(k string, labels map[string]string) {
+	store.RegisterInfo(k, labels)
+}
+
+// UpdateInfo updates an info metric's labels using the default
+// metric store
+func UpdateInfo(k string, labels map[string]string) {
+	store.UpdateInfo(k, labels)
+}
+
+// InfoValues returns info metric label sets stored in the default
+// metric store
+func InfoValues() (res map[string]map[string]string) {
+	return store.InfoValues()
+}
+
 // Values returns values stored in the default metric store
 func Values() (res map[string]int64) {
 	return store.Values()
 }
 
+// TypedValues returns values, with their metric type, stored in the
+// default metric store
+func TypedValues() (res map[string]TypedValue) {
+	return store.TypedValues()
+}
+
 // Print logs using the default metric store
-func Print(dur time.Duration) (ch chan bool) {
+func Print(dur time.Duration) (stop func()) {
 	return store.Print(dur)
 }
 
+// Report sends metrics from the default metric store to r on every
+// interval
+func Report(dur time.Duration, r Reporter) (stop func()) {
+	return store.Report(dur, r)
+}
+
+// Stop cancels every Print/Report loop running against the default
+// metric store
+func Stop() {
+	store.Stop()
+}
+
 //   Store
 // ---------
 
 // Store is a collection of application metrics
 type Store struct {
-	head string
-	vals map[string]metric
-	subs map[string]*Store
+	mtx     sync.RWMutex
+	head    string
+	vals    map[string]metric
+	infos   map[string]*infoMetric
+	subs    map[string]*Store
+	cancels []context.CancelFunc
 }
 
 func newStore(head string) *Store {
 	return &Store{
-		head: head,
-		vals: map[string]metric{},
-		subs: map[string]*Store{},
+		head:  head,
+		vals:  map[string]metric{},
+		infos: map[string]*infoMetric{},
+		subs:  map[string]*Store{},
 	}
 }
 
 // New returns a child store by extending the header
 func (s *Store) New(head string) (sub *Store) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
 	if sub, ok := s.subs[head]; ok {
 		return sub
 	}
 
-	key := s.head + "." + head
-	return newStore(key)
+	sub = newStore(s.head + "." + head)
+	s.subs[head] = sub
+	return sub
 }
 
 // Register a new metric to measure later
 func (s *Store) Register(k string, t Type) {
 	k = s.head + ":" + k
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
 	if _, ok := s.vals[k]; !ok {
 		switch t {
 		case Gauge:
@@ -87,6 +140,12 @@ func (s *Store) Register(k string, t Type) {
 			s.vals[k] = &counter{}
 		case Rate:
 			s.vals[k] = &rate{}
+		case Histogram:
+			s.vals[k] = newHistogram()
+		case Meter:
+			s.vals[k] = newMeter()
+		case Timer:
+			s.vals[k] = newTimer()
 		}
 	}
 }
@@ -95,48 +154,193 @@ func (s *Store) Register(k string, t Type) {
 // registered before tracking values.
 func (s *Store) Track(k string, n int64) {
 	k = s.head + ":" + k
-	if m, ok := s.vals[k]; ok {
-		m.Track(n)
-	} else {
+
+	s.mtx.RLock()
+	m, ok := s.vals[k]
+	s.mtx.RUnlock()
+
+	if !ok {
 		logger.Debug("unregistered monitor key", k)
-		s.vals[k] = &counter{}
+
+		s.mtx.Lock()
+		if m, ok = s.vals[k]; !ok {
+			m = &counter{}
+			s.vals[k] = m
+		}
+		s.mtx.Unlock()
 	}
+
+	m.Track(n)
+}
+
+// RegisterInfo registers a labelled info metric to update later. Info
+// metrics carry a set of string labels rather than a number, so they
+// use their own registration path instead of Register/Type.
+func (s *Store) RegisterInfo(k string, labels map[string]string) {
+	k = s.head + ":" + k
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.infos[k]; !ok {
+		s.infos[k] = newInfoMetric(labels)
+	}
+}
+
+// UpdateInfo replaces an info metric's labels. The metric should be
+// registered with RegisterInfo before updating it.
+func (s *Store) UpdateInfo(k string, labels map[string]string) {
+	k = s.head + ":" + k
+
+	s.mtx.RLock()
+	m, ok := s.infos[k]
+	s.mtx.RUnlock()
+
+	if !ok {
+		logger.Debug("unregistered monitor info key", k)
+
+		s.mtx.Lock()
+		if m, ok = s.infos[k]; !ok {
+			m = newInfoMetric(nil)
+			s.infos[k] = m
+		}
+		s.mtx.Unlock()
+	}
+
+	m.update(labels)
+}
+
+// InfoValues returns every registered info metric's labels, keyed the
+// same way TypedValues is.
+func (s *Store) InfoValues() (res map[string]map[string]string) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	res = map[string]map[string]string{}
+	for k, m := range s.infos {
+		res[k] = m.labels()
+	}
+
+	return res
+}
+
+// allInfoValues returns InfoValues for this store and every sub-store,
+// recursively, keyed the same way InfoValues is.
+func (s *Store) allInfoValues() (res map[string]map[string]string) {
+	res = s.InfoValues()
+
+	for _, sub := range s.childStores() {
+		for k, labels := range sub.allInfoValues() {
+			res[k] = labels
+		}
+	}
+
+	return res
 }
 
 // Values returns all values as a map
 func (s *Store) Values() (res map[string]int64) {
 	res = map[string]int64{}
+	for k, tv := range s.TypedValues() {
+		res[k] = tv.Value
+	}
+
+	return res
+}
+
+// TypedValues returns all values, along with their metric type, as a
+// map. Unlike Values, this preserves whether a metric is a gauge,
+// counter, rate, etc. so reporters can render it correctly.
+func (s *Store) TypedValues() (res map[string]TypedValue) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	res = map[string]TypedValue{}
 	for k, m := range s.vals {
-		res[k] = m.Value()
+		res[k] = TypedValue{Type: m.Kind(), Value: m.Value()}
 	}
 
 	return res
 }
 
+// allTypedValues returns TypedValues for this store and every
+// sub-store, recursively, keyed the same way TypedValues is.
+func (s *Store) allTypedValues() (res map[string]TypedValue) {
+	res = s.TypedValues()
+
+	for _, sub := range s.childStores() {
+		for k, tv := range sub.allTypedValues() {
+			res[k] = tv
+		}
+	}
+
+	return res
+}
+
+// childStores returns a snapshot of this store's sub-stores, safe to
+// range over without holding any lock.
+func (s *Store) childStores() (subs []*Store) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	subs = make([]*Store, 0, len(s.subs))
+	for _, sub := range s.subs {
+		subs = append(subs, sub)
+	}
+
+	return subs
+}
+
 // Print logs application metrics to stdout with given interval
 // The "metrics" log level should be enabled for this to work.
 // It will also log all children metric stores recursively.
-func (s *Store) Print(dur time.Duration) (ch chan bool) {
-	ch = make(chan bool)
+func (s *Store) Print(dur time.Duration) (stop func()) {
+	return s.Report(dur, logReporter{})
+}
+
+// Report sends this store's metrics, and every sub-store's metrics
+// recursively, to r on every interval. Multiple reporters may be
+// registered against the same store concurrently, each on its own
+// Report call. Call the returned stop function, or Stop, to end the
+// loop.
+func (s *Store) Report(dur time.Duration, r Reporter) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.mtx.Lock()
+	s.cancels = append(s.cancels, cancel)
+	s.mtx.Unlock()
 
 	go func() {
 		for {
 			select {
-			case <-ch:
-				break
+			case <-ctx.Done():
+				return
 			case <-time.After(dur):
-				s.log()
+				if err := r.Report(s.allTypedValues()); err != nil {
+					logger.Debug("monitor: report failed", err)
+				}
+				if ir, ok := r.(InfoReporter); ok {
+					if err := ir.ReportInfo(s.allInfoValues()); err != nil {
+						logger.Debug("monitor: info report failed", err)
+					}
+				}
 			}
 		}
 	}()
 
-	return ch
+	return cancel
 }
 
-func (s *Store) log() {
-	logger.Print("metrics", s.head, s.Values())
-	for _, sub := range s.subs {
-		sub.log()
+// Stop cancels every Print/Report loop started on this store. It does
+// not affect sub-stores.
+func (s *Store) Stop() {
+	s.mtx.Lock()
+	cancels := s.cancels
+	s.cancels = nil
+	s.mtx.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
 	}
 }
 
@@ -146,6 +350,7 @@ func (s *Store) log() {
 type metric interface {
 	Value() (val int64)
 	Track(n int64)
+	Kind() (t Type)
 }
 
 //   gauge
@@ -155,19 +360,21 @@ type gauge struct {
 	val int64
 }
 
+// Value returns the last tracked value. Unlike Track, reading a gauge
+// never changes it, so concurrent readers (e.g. several Reporters)
+// all see the same value.
 func (c *gauge) Value() (val int64) {
-	val = atomic.LoadInt64(&c.val)
-	for !atomic.CompareAndSwapInt64(&c.val, val, 0) {
-		val = atomic.LoadInt64(&c.val)
-	}
-
-	return val
+	return atomic.LoadInt64(&c.val)
 }
 
 func (c *gauge) Track(n int64) {
 	atomic.StoreInt64(&c.val, n)
 }
 
+func (c *gauge) Kind() (t Type) {
+	return Gauge
+}
+
 //   counter
 // -----------
 
@@ -175,36 +382,50 @@ type counter struct {
 	val int64
 }
 
+// Value returns the running total tracked so far. Counters are
+// monotonic: reading one never resets it, so the exporter/reporter
+// layer (or a Prometheus rate()/increase()) is responsible for
+// computing deltas, and concurrent readers can't cannibalize each
+// other's view of the count.
 func (c *counter) Value() (val int64) {
-	val = atomic.LoadInt64(&c.val)
-	for !atomic.CompareAndSwapInt64(&c.val, val, 0) {
-		val = atomic.LoadInt64(&c.val)
-	}
-
-	return val
+	return atomic.LoadInt64(&c.val)
 }
 
 func (c *counter) Track(n int64) {
 	atomic.AddInt64(&c.val, n)
 }
 
+func (c *counter) Kind() (t Type) {
+	return Counter
+}
+
 //   rate
 // --------
 
+// rate reports events/second over a 1-second window. For longer,
+// smoother windows (1/5/15-minute), use Meter instead.
 type rate struct {
-	mtx sync.Mutex
-	val int64
-	ts0 int64
+	mtx    sync.Mutex
+	val    int64
+	ts0    int64
+	cached int64
 }
 
+// Value returns events/second over the window since the last second
+// boundary. The first read after a boundary computes and caches the
+// rate and starts a new window; any other reader calling Value within
+// the same second gets that same cached rate instead of racing to
+// drain val first, so Rate is safe to read from multiple concurrent
+// Reporters/scrapes.
 func (c *rate) Value() (val int64) {
 	c.mtx.Lock()
 
 	if now := time.Now().Unix(); now > c.ts0 {
-		val = c.val / (now - c.ts0)
+		c.cached = c.val / (now - c.ts0)
 		c.ts0 = now
 		c.val = 0
 	}
+	val = c.cached
 
 	c.mtx.Unlock()
 	return val
@@ -220,3 +441,7 @@ func (c *rate) Track(n int64) {
 
 	c.mtx.Unlock()
 }
+
+func (c *rate) Kind() (t Type) {
+	return Rate
+}
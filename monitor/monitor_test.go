@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestStoreConcurrentTrackAndValues hammers Track from many goroutines
+// while Values is read concurrently, to be run with -race. It exists
+// to catch the registry-map and read-modify-write races that used to
+// live in Store.
+func TestStoreConcurrentTrackAndValues(t *testing.T) {
+	s := newStore("test")
+	s.Register("hits", Counter)
+	s.Register("level", Gauge)
+
+	const goroutines = 50
+	const iterations = 200
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines * 2)
+
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				s.Track("hits", 1)
+				s.Track("level", int64(j))
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				s.Values()
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// TestStoreCounterValueIsCumulative ensures reading a counter does
+// not reset it, so multiple readers (scrapes, reporters) in the same
+// interval all see the same running total instead of splitting it.
+func TestStoreCounterValueIsCumulative(t *testing.T) {
+	s := newStore("test")
+	s.Register("hits", Counter)
+	s.Track("hits", 100)
+
+	for i := 0; i < 3; i++ {
+		if got := s.Values()["test:hits"]; got != 100 {
+			t.Fatalf("read %d: counter value = %d, want 100", i, got)
+		}
+	}
+}
+
+// TestRateValueIsSharedAcrossReaders ensures two readers calling
+// Value within the same second both see the freshly computed rate,
+// instead of the first reader draining it and the second getting 0.
+func TestRateValueIsSharedAcrossReaders(t *testing.T) {
+	r := &rate{val: 10, ts0: time.Now().Unix() - 1}
+
+	first := r.Value()
+	second := r.Value()
+
+	if first != 10 || second != 10 {
+		t.Fatalf("expected both readers to see rate 10, got %d then %d", first, second)
+	}
+}
+
+// TestStoreNewRegistersSubStore ensures New actually stores the child
+// so it is visible to recursive walks (Print, Report, the Prometheus
+// handler).
+func TestStoreNewRegistersSubStore(t *testing.T) {
+	s := newStore("test")
+	sub := s.New("worker")
+
+	if got := s.New("worker"); got != sub {
+		t.Fatalf("New should return the same sub-store on repeat calls, got %p want %p", got, sub)
+	}
+
+	if len(s.childStores()) != 1 {
+		t.Fatalf("expected 1 sub-store, got %d", len(s.childStores()))
+	}
+}
+
+// TestStoreReportStop ensures that stopping a Report loop actually
+// ends its goroutine instead of only unblocking a select.
+func TestStoreReportStop(t *testing.T) {
+	s := newStore("test")
+	s.Register("hits", Counter)
+
+	reported := make(chan struct{}, 1)
+	stop := s.Report(0, reporterFunc(func(map[string]TypedValue) error {
+		select {
+		case reported <- struct{}{}:
+		default:
+		}
+		return nil
+	}))
+
+	<-reported
+	stop()
+
+	// Let an iteration already in flight finish, then drain its report.
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-reported:
+	default:
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-reported:
+		t.Fatal("Report loop kept running after stop")
+	default:
+	}
+}
+
+type reporterFunc func(map[string]TypedValue) error
+
+func (f reporterFunc) Report(metrics map[string]TypedValue) error {
+	return f(metrics)
+}
@@ -0,0 +1,59 @@
+package monitor
+
+import "github.com/kadirahq/go-tools/logger"
+
+// TypedValue is a metric's current value along with the kind of
+// metric it came from, so a Reporter can render it (e.g. as a
+// Prometheus counter vs gauge) without losing that information.
+type TypedValue struct {
+	Type  Type
+	Value int64
+}
+
+// Reporter receives a flat snapshot of a store's metrics, keyed the
+// same way Store.Values is ("head:name"), on every Report interval.
+type Reporter interface {
+	Report(metrics map[string]TypedValue) error
+}
+
+// InfoReporter is an optional extension a Reporter can implement to
+// also receive info metrics' label sets, which don't fit the plain
+// int64 TypedValue shape Report carries. Store.Report calls ReportInfo
+// after Report when the configured Reporter implements this.
+type InfoReporter interface {
+	ReportInfo(infos map[string]map[string]string) error
+}
+
+//   logReporter
+// ---------------
+
+// logReporter is the Reporter backing Store.Print: it groups metrics
+// by their originating store and logs one line per store, preserving
+// Print's historical output shape.
+type logReporter struct{}
+
+func (logReporter) Report(metrics map[string]TypedValue) error {
+	grouped := map[string]map[string]int64{}
+	for k, tv := range metrics {
+		head, leaf := splitKey(k)
+		if grouped[head] == nil {
+			grouped[head] = map[string]int64{}
+		}
+		grouped[head][leaf] = tv.Value
+	}
+
+	for head, vals := range grouped {
+		logger.Print("metrics", head, vals)
+	}
+
+	return nil
+}
+
+func (logReporter) ReportInfo(infos map[string]map[string]string) error {
+	for k, labels := range infos {
+		head, leaf := splitKey(k)
+		logger.Print("metrics", head, leaf, labels)
+	}
+
+	return nil
+}
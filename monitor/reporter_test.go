@@ -0,0 +1,55 @@
+package monitor
+
+import "testing"
+
+// TestConcurrentReportersSeeSameValues ensures two reporters running
+// against the same store don't split a counter's value between them
+// now that reads are non-destructive.
+func TestConcurrentReportersSeeSameValues(t *testing.T) {
+	s := newStore("test")
+	s.Register("hits", Counter)
+	s.Track("hits", 100)
+
+	seenA := make(chan int64, 1)
+	seenB := make(chan int64, 1)
+
+	stopA := s.Report(0, reporterFunc(func(metrics map[string]TypedValue) error {
+		select {
+		case seenA <- metrics["test:hits"].Value:
+		default:
+		}
+		return nil
+	}))
+	defer stopA()
+
+	stopB := s.Report(0, reporterFunc(func(metrics map[string]TypedValue) error {
+		select {
+		case seenB <- metrics["test:hits"].Value:
+		default:
+		}
+		return nil
+	}))
+	defer stopB()
+
+	if a, b := <-seenA, <-seenB; a != 100 || b != 100 {
+		t.Fatalf("expected both reporters to see 100, got %d and %d", a, b)
+	}
+}
+
+func TestInfluxEscape(t *testing.T) {
+	if got, want := influxEscape("us east, zone=1"), `us\ east\,\ zone\=1`; got != want {
+		t.Fatalf("influxEscape(%q) = %q, want %q", "us east, zone=1", got, want)
+	}
+}
+
+func TestStatsDEscape(t *testing.T) {
+	if got, want := statsDEscape("svc:1|x"), "svc_1_x"; got != want {
+		t.Fatalf("statsDEscape = %q, want %q", got, want)
+	}
+}
+
+func TestGraphiteEscape(t *testing.T) {
+	if got, want := graphiteEscape("us east"), "us_east"; got != want {
+		t.Fatalf("graphiteEscape = %q, want %q", got, want)
+	}
+}
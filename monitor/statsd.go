@@ -0,0 +1,66 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// StatsDReporter reports metrics to a StatsD daemon over UDP, using
+// "name:value|type" framing, one line per metric.
+type StatsDReporter struct {
+	conn net.Conn
+}
+
+// NewStatsDReporter dials the StatsD daemon at addr (host:port). The
+// connection is UDP, so Report never blocks on the remote end.
+func NewStatsDReporter(addr string) (r *StatsDReporter, err error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatsDReporter{conn: conn}, nil
+}
+
+// Report writes every metric as a single StatsD packet.
+func (r *StatsDReporter) Report(metrics map[string]TypedValue) (err error) {
+	var buf bytes.Buffer
+	for k, tv := range metrics {
+		head, leaf := splitKey(k)
+		fmt.Fprintf(&buf, "%s.%s:%d|%s\n", statsDEscape(head), statsDEscape(leaf), tv.Value, statsDType(tv.Type))
+	}
+
+	_, err = r.conn.Write(buf.Bytes())
+	return err
+}
+
+// statsDType maps a monitor.Type to its StatsD type suffix.
+func statsDType(t Type) string {
+	switch t {
+	case Counter:
+		return "c"
+	case Timer:
+		return "ms"
+	default:
+		return "g"
+	}
+}
+
+// statsDEscape sanitizes a metric name component for StatsD's
+// "name:value|type" framing. StatsD has no escaping syntax, so the
+// delimiter characters (":", "|", "@"), whitespace and newlines are
+// replaced with "_" instead.
+var statsDReplacer = strings.NewReplacer(
+	":", "_",
+	"|", "_",
+	"@", "_",
+	" ", "_",
+	"\t", "_",
+	"\n", "_",
+)
+
+func statsDEscape(s string) string {
+	return statsDReplacer.Replace(s)
+}
@@ -0,0 +1,49 @@
+package monitor
+
+// TimerSnapshot is a point-in-time summary of a timer: the call rate
+// alongside the latency distribution.
+type TimerSnapshot struct {
+	Meter     MeterSnapshot
+	Histogram HistogramSnapshot
+}
+
+//   timer
+// --------
+
+// timer composes a meter, to track how often it is called, with a
+// histogram, to track the distribution of the values (typically
+// latencies) it is called with.
+type timer struct {
+	meter *meter
+	hist  *histogram
+}
+
+func newTimer() *timer {
+	return &timer{
+		meter: newMeter(),
+		hist:  newHistogram(),
+	}
+}
+
+// Track records a single occurrence with duration/value n.
+func (t *timer) Track(n int64) {
+	t.meter.Track(1)
+	t.hist.Track(n)
+}
+
+// Value returns the mean tracked value, same as a bare Histogram.
+func (t *timer) Value() (val int64) {
+	return t.hist.Value()
+}
+
+func (t *timer) Kind() (k Type) {
+	return Timer
+}
+
+// Snapshot returns the current call rate and value distribution.
+func (t *timer) Snapshot() (snap TimerSnapshot) {
+	return TimerSnapshot{
+		Meter:     t.meter.Snapshot(),
+		Histogram: t.hist.Snapshot(),
+	}
+}